@@ -0,0 +1,38 @@
+// Command snakeserver exposes the snake engine over HTTP/JSON so that bots
+// or alternative front-ends can play it without a terminal.
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/golang/glog"
+
+	"snakegame/pkg/metrics"
+	"snakegame/pkg/server"
+)
+
+var (
+	addr         = flag.String("addr", ":8080", "address to listen on")
+	width        = flag.Int("width", 20, "board width")
+	height       = flag.Int("height", 20, "board height")
+	tickInterval = flag.Duration("tick_interval", 100*time.Millisecond, "how often sessions advance")
+	idleTTLTicks = flag.Int("idle_ttl_ticks", 600, "ticks a session may go without input before it expires")
+	metricsAddr  = flag.String("metrics_addr", "", "address to serve metrics on, e.g. :6060; empty disables metrics")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+	srv := server.NewServer(*width, *height, *tickInterval, *idleTTLTicks)
+	if *metricsAddr != "" {
+		srv.Metrics = metrics.NewRecorder()
+		go func() {
+			glog.Infof("Starting metrics listener on %s", *metricsAddr)
+			glog.Error(http.ListenAndServe(*metricsAddr, srv.Metrics.Handler()))
+		}()
+	}
+	glog.Infof("Starting snakeserver on %s", *addr)
+	glog.Fatal(http.ListenAndServe(*addr, srv.Handler()))
+}
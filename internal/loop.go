@@ -1,41 +1,93 @@
-// Package internal contains the game's core loop.
+// Package internal adapts the headless snake engine in pkg/snake to a
+// tcell terminal screen.
 package internal
 
 import (
 	"context"
 	"fmt"
-	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/gdamore/tcell"
 	"github.com/golang/glog"
+
+	"snakegame/pkg/metrics"
+	"snakegame/pkg/snake"
 )
 
-type keyEvent int
+var directions = map[tcell.Key]snake.Direction{
+	tcell.KeyDown:  snake.Down,
+	tcell.KeyUp:    snake.Up,
+	tcell.KeyLeft:  snake.Left,
+	tcell.KeyRight: snake.Right,
+}
 
 const (
-	down keyEvent = iota
-	up
-	left
-	right
+	// scoreMultiplier is how many points each food is worth.
+	scoreMultiplier = 10
+	// foodsPerLevel is how many foods it takes to advance a level.
+	foodsPerLevel = 5
+	// baseTickInterval is the tick interval at level 1.
+	baseTickInterval = 100 * time.Millisecond
+	// tickIntervalStep is how much faster each level beyond 1 ticks.
+	tickIntervalStep = 5 * time.Millisecond
+	// minTickInterval is the fastest the game will ever tick.
+	minTickInterval = 40 * time.Millisecond
 )
 
-type position struct {
-	x int
-	y int
+// humanAgent reads its next move from the arrow keys most recently seen by
+// Loop's event-polling goroutine. It starts out moving Right instead of
+// waiting for a first keypress.
+type humanAgent struct {
+	mu  *sync.Mutex
+	dir *snake.Direction
+}
+
+// NextMove implements snake.Agent.
+func (h *humanAgent) NextMove(snake.State) snake.Direction {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return *h.dir
+}
+
+// Options configures Loop. The board dimensions are owned by the engine,
+// not the terminal: the renderer centers and clips the board within
+// whatever size the terminal happens to be.
+type Options struct {
+	Width, Height int
+	Mode          snake.Mode
+	NumObstacles  int
+	RandIntFn     func(n int) int
+	// Agent picks the snake's next move each tick. Nil means a human
+	// playing with the arrow keys.
+	Agent snake.Agent
+	// Metrics, if non-nil, is fed a sample of every Tick.
+	Metrics *metrics.Recorder
+}
+
+func (o Options) newGame() *snake.Game {
+	return snake.NewGame(snake.Config{
+		Width:        o.Width,
+		Height:       o.Height,
+		RandIntFn:    o.RandIntFn,
+		Mode:         o.Mode,
+		NumObstacles: o.NumObstacles,
+	})
 }
 
-type game struct {
-	snake []position
-	food  map[position]struct{}
+// level returns the level for a given number of foods eaten: 1 to start,
+// increasing every foodsPerLevel foods.
+func level(foodEaten int) int {
+	return foodEaten/foodsPerLevel + 1
 }
 
-func newGame() *game {
-	return &game{
-		snake: []position{{0, 0}},
-		food:  map[position]struct{}{{0, 1}: {}},
+// tickInterval returns how often the game should tick at the given level.
+func tickInterval(lvl int) time.Duration {
+	d := baseTickInterval - time.Duration(lvl-1)*tickIntervalStep
+	if d < minTickInterval {
+		return minTickInterval
 	}
+	return d
 }
 
 func drawText(s tcell.Screen, x1, y1, x2, y2 int, style tcell.Style, text string) {
@@ -54,64 +106,43 @@ func drawText(s tcell.Screen, x1, y1, x2, y2 int, style tcell.Style, text string
 	}
 }
 
-func (g *game) drawGame(s tcell.Screen) {
-	for _, p := range g.snake {
-		s.SetContent(p.x, p.y, 's', nil, tcell.StyleDefault)
-	}
-	for p := range g.food {
-		s.SetContent(p.x, p.y, 'f', nil, tcell.StyleDefault)
-	}
+// drawHUD renders the score, level, and snake length on the HUD line above
+// the play area.
+func drawHUD(s tcell.Screen, state snake.State) {
+	cols, _ := s.Size()
+	text := fmt.Sprintf("Score: %d  Level: %d  Length: %d", state.FoodEaten*scoreMultiplier, level(state.FoodEaten), len(state.Snake))
+	drawText(s, 0, 0, cols, 0, tcell.StyleDefault, text)
 }
 
-func (g *game) event(k keyEvent, rows, cols int) bool {
-	glog.V(2).Infof("Game: %+v", g)
-	// Save the tail so that if the snake ate food we can append the tail to
-	// grow by 1 unit.
-	tail := g.snake[len(g.snake)-1]
-	for i := len(g.snake) - 1; i > 0; i-- {
-		g.snake[i] = g.snake[i-1]
-	}
-	// x, y coordinate system starting from 0, 0 at the upper-left corner.
-	switch k {
-	case down:
-		g.snake[0].y++
-	case up:
-		g.snake[0].y--
-	case left:
-		g.snake[0].x--
-	case right:
-		g.snake[0].x++
-	}
-	// Range check the snake to end the game if it goes off the screen.
-	if g.snake[0].x < 0 || g.snake[0].y < 0 || g.snake[0].x >= rows || g.snake[0].y >= cols {
-		return false
-	}
-	// Check that the snake hasn't run into itself.
-	positions := map[position]struct{}{}
-	for _, p := range g.snake {
-		if _, ok := positions[p]; ok {
-			return false
-		} else {
-			positions[p] = struct{}{}
+// drawGame renders g's grid onto the screen, centered within it below the
+// HUD line.
+func drawGame(s tcell.Screen, g *snake.Game) {
+	cols, rows := s.Size()
+	rows--
+	offsetX := (cols - g.Width) / 2
+	offsetY := 1 + (rows-g.Height)/2
+	grid := g.Grid()
+	for y, row := range grid {
+		for x, cell := range row {
+			sx, sy := offsetX+x, offsetY+y
+			if sx < 0 || sx >= cols || sy < 1 || sy >= rows+1 {
+				continue
+			}
+			switch cell {
+			case snake.CellSnake:
+				s.SetContent(sx, sy, 's', nil, tcell.StyleDefault)
+			case snake.CellFood:
+				s.SetContent(sx, sy, 'f', nil, tcell.StyleDefault)
+			case snake.CellObstacle:
+				s.SetContent(sx, sy, '#', nil, tcell.StyleDefault)
+			}
 		}
 	}
-	// Grow the snake when it eats food.
-	if _, ok := g.food[g.snake[0]]; ok {
-		glog.V(2).Infof("Ate food: x=%v y=%v", g.snake[0].x, g.snake[0].y)
-		g.snake = append(g.snake, tail)
-		delete(g.food, g.snake[0])
-		x := rand.Intn(rows)
-		y := rand.Intn(cols)
-		glog.V(2).Infof("New food: x=%v y=%v", x, y)
-		g.food[position{x, y}] = struct{}{}
-	}
-	glog.V(2).Infof("Game: %+v", g)
-	return true
 }
 
 // Loop starts the game loop. Returns an error if the screen can't be created.
-// Cancelling the context will end the loop. Ctrl-C from the user too.
-func Loop(ctx context.Context) error {
+// Cancelling the context will end the loop. Ctrl-C or 'q' from the user too.
+func Loop(ctx context.Context, opts Options) error {
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	s, err := tcell.NewScreen()
@@ -122,27 +153,34 @@ func Loop(ctx context.Context) error {
 		return fmt.Errorf("init problem: %v", err)
 	}
 	defer s.Fini()
-	g := newGame()
-	directions := map[tcell.Key]keyEvent{
-		tcell.KeyDown:  down,
-		tcell.KeyUp:    up,
-		tcell.KeyLeft:  left,
-		tcell.KeyRight: right,
-	}
-	ticker := time.NewTicker(100 * time.Millisecond)
+	g := opts.newGame()
+	ticker := time.NewTicker(baseTickInterval)
 	defer ticker.Stop()
-	var event *tcell.EventKey
-	var mu sync.Mutex
+	var (
+		mu            sync.Mutex
+		humanDir      = snake.Right
+		paused        bool
+		finished      bool
+		restart       bool
+		curLevel      = 1
+		prevFoodEaten int
+	)
+	agent := opts.Agent
+	if agent == nil {
+		agent = &humanAgent{mu: &mu, dir: &humanDir}
+	}
 	go func() {
-		var finished bool
 		for {
+			mu.Lock()
+			f := finished
+			mu.Unlock()
 			s.Clear()
-			if finished {
-				// Stop processing key events when the game is over.
-				text := "game over"
+			if f {
+				text := "game over - press r to restart, q to quit"
 				drawText(s, 0, 0, len(text), 0, tcell.StyleDefault, text)
 			} else {
-				g.drawGame(s)
+				drawHUD(s, g.State())
+				drawGame(s, g)
 			}
 			s.Show()
 			select {
@@ -151,16 +189,42 @@ func Loop(ctx context.Context) error {
 			case <-ticker.C:
 			}
 			mu.Lock()
-			e := event
+			doRestart := restart
+			restart = false
+			isPaused := paused
+			mu.Unlock()
+			if doRestart {
+				g = opts.newGame()
+				curLevel = 1
+				prevFoodEaten = 0
+				ticker.Reset(baseTickInterval)
+				mu.Lock()
+				finished = false
+				mu.Unlock()
+				continue
+			}
+			mu.Lock()
+			f = finished
 			mu.Unlock()
-			if e == nil {
+			if f || isPaused {
 				continue
 			}
-			d := directions[e.Key()]
-			glog.V(2).Infof("Key: %v", d)
-			rows, cols := s.Size()
-			if !g.event(d, rows, cols) {
+			d := agent.NextMove(g.State())
+			glog.V(2).Infof("Move: %v", d)
+			start := time.Now()
+			state, alive := g.Tick(d)
+			if opts.Metrics != nil {
+				prevFoodEaten = opts.Metrics.ObserveTick(state, alive, time.Since(start), prevFoodEaten)
+			}
+			if !alive {
+				mu.Lock()
 				finished = true
+				mu.Unlock()
+				continue
+			}
+			if lvl := level(state.FoodEaten); lvl != curLevel {
+				curLevel = lvl
+				ticker.Reset(tickInterval(curLevel))
 			}
 		}
 	}()
@@ -178,11 +242,25 @@ func Loop(ctx context.Context) error {
 			case tcell.KeyCtrlC:
 				return nil
 			case tcell.KeyDown, tcell.KeyUp, tcell.KeyLeft, tcell.KeyRight:
-				// A key event updates the current event being drawn propagated
-				// forward by the goroutine.
+				// Arrow keys steer the human agent; harmless no-op for bots.
 				mu.Lock()
-				event = e
+				humanDir = directions[e.Key()]
 				mu.Unlock()
+			case tcell.KeyRune:
+				switch e.Rune() {
+				case 'p':
+					mu.Lock()
+					paused = !paused
+					mu.Unlock()
+				case 'r':
+					mu.Lock()
+					if finished {
+						restart = true
+					}
+					mu.Unlock()
+				case 'q':
+					return nil
+				}
 			}
 		}
 	}
@@ -3,20 +3,79 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"math/rand"
-	"snakegame/internal"
+	"net/http"
+	"os"
 	"time"
 
 	"github.com/golang/glog"
+
+	"snakegame/internal"
+	"snakegame/pkg/metrics"
+	"snakegame/pkg/snake"
 )
 
+var (
+	mode        = flag.String("mode", "classic", "game mode: classic, wrap, or obstacles")
+	width       = flag.Int("width", 20, "board width")
+	height      = flag.Int("height", 20, "board height")
+	obstacles   = flag.Int("obstacles", 10, "number of obstacles to seed in obstacles mode")
+	seed        = flag.Int64("seed", 0, "seed for the random number generator; 0 means use the current time")
+	metricsAddr = flag.String("metrics_addr", "", "address to serve metrics on, e.g. :6060; empty disables metrics")
+	agent       = flag.String("agent", "human", "who plays: human, bfs, or greedy")
+)
+
+var modesByName = map[string]snake.Mode{
+	"classic":   snake.ModeClassic,
+	"wrap":      snake.ModeWrap,
+	"obstacles": snake.ModeObstacles,
+}
+
+var agentsByName = map[string]snake.Agent{
+	"human":  nil,
+	"bfs":    snake.BFSAgent{},
+	"greedy": snake.GreedyAgent{},
+}
+
 func main() {
 	flag.Parse()
 	defer glog.Flush()
-	rand.Seed(time.Now().Unix())
+	m, ok := modesByName[*mode]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown mode %q\n", *mode)
+		os.Exit(1)
+	}
+	a, ok := agentsByName[*agent]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown agent %q\n", *agent)
+		os.Exit(1)
+	}
+	s := *seed
+	if s == 0 {
+		s = time.Now().Unix()
+	}
+	rnd := rand.New(rand.NewSource(s))
 	ctx := context.Background()
+	var rec *metrics.Recorder
+	if *metricsAddr != "" {
+		rec = metrics.NewRecorder()
+		go func() {
+			glog.Infof("Starting metrics listener on %s", *metricsAddr)
+			glog.Error(http.ListenAndServe(*metricsAddr, rec.Handler()))
+		}()
+	}
 	glog.Infof("Starting loop")
-	if err := internal.Loop(ctx); err != nil {
+	opts := internal.Options{
+		Width:        *width,
+		Height:       *height,
+		Mode:         m,
+		NumObstacles: *obstacles,
+		RandIntFn:    rnd.Intn,
+		Agent:        a,
+		Metrics:      rec,
+	}
+	if err := internal.Loop(ctx, opts); err != nil {
 		glog.Fatal(err)
 	}
 }
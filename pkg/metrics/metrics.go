@@ -0,0 +1,131 @@
+// Package metrics instruments the snake engine with go-metrics-style
+// counters, a timer, and a gauge, and serves them over HTTP as JSON or
+// Prometheus text format. This makes the game usable as a demo/load-test
+// target and gives a hook for future play analysis.
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+
+	"snakegame/pkg/snake"
+)
+
+// Recorder holds the metrics for a single running game (or server
+// session) and the registry they're reported through.
+type Recorder struct {
+	registry metrics.Registry
+
+	Ticks       metrics.Counter
+	FoodsEaten  metrics.Counter
+	Deaths      metrics.Counter
+	TickLatency metrics.Timer
+	SnakeLength metrics.Gauge
+}
+
+// NewRecorder returns a Recorder with all metrics registered under a fresh
+// registry.
+func NewRecorder() *Recorder {
+	r := metrics.NewRegistry()
+	return &Recorder{
+		registry:    r,
+		Ticks:       metrics.NewRegisteredCounter("snake.ticks", r),
+		FoodsEaten:  metrics.NewRegisteredCounter("snake.foods_eaten", r),
+		Deaths:      metrics.NewRegisteredCounter("snake.deaths", r),
+		TickLatency: metrics.NewRegisteredTimer("snake.tick_latency", r),
+		SnakeLength: metrics.NewRegisteredGauge("snake.snake_length", r),
+	}
+}
+
+// ObserveTick records a single Tick: how long it took to process, the
+// snake's new length, and whether it ate food or died. Since a Recorder is
+// shared across every concurrently running game (e.g. one per server
+// session), callers track their own game's last FoodEaten count and pass it
+// in as prevFoodEaten; ObserveTick returns the new count to feed into the
+// next call. This keeps FoodsEaten deltas correct per-game instead of
+// comparing across unrelated games.
+func (r *Recorder) ObserveTick(state snake.State, alive bool, took time.Duration, prevFoodEaten int) (newFoodEaten int) {
+	r.Ticks.Inc(1)
+	r.TickLatency.Update(took)
+	r.SnakeLength.Update(int64(len(state.Snake)))
+	if delta := state.FoodEaten - prevFoodEaten; delta > 0 {
+		r.FoodsEaten.Inc(int64(delta))
+		prevFoodEaten = state.FoodEaten
+	}
+	if !alive {
+		r.Deaths.Inc(1)
+	}
+	return prevFoodEaten
+}
+
+// Handler serves the recorder's metrics as JSON by default, or as
+// Prometheus text format when the request asks for
+// "?format=prometheus".
+func (r *Recorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Query().Get("format") == "prometheus" {
+			w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+			writePrometheus(w, r.registry)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(snapshot(r.registry)); err != nil {
+			http.Error(w, fmt.Sprintf("problem encoding metrics: %v", err), http.StatusInternalServerError)
+		}
+	})
+}
+
+// snapshot flattens the registry into a JSON-friendly map of metric name
+// to its relevant fields.
+func snapshot(registry metrics.Registry) map[string]interface{} {
+	out := map[string]interface{}{}
+	registry.Each(func(name string, i interface{}) {
+		switch m := i.(type) {
+		case metrics.Counter:
+			out[name] = m.Count()
+		case metrics.Gauge:
+			out[name] = m.Value()
+		case metrics.Timer:
+			out[name] = map[string]interface{}{
+				"count":  m.Count(),
+				"mean":   m.Mean(),
+				"max":    m.Max(),
+				"min":    m.Min(),
+				"p99_ns": m.Percentile(0.99),
+			}
+		}
+	})
+	return out
+}
+
+// writePrometheus renders the registry in Prometheus text exposition
+// format.
+func writePrometheus(w http.ResponseWriter, registry metrics.Registry) {
+	registry.Each(func(name string, i interface{}) {
+		metric := prometheusName(name)
+		switch m := i.(type) {
+		case metrics.Counter:
+			fmt.Fprintf(w, "# TYPE %s counter\n%s %d\n", metric, metric, m.Count())
+		case metrics.Gauge:
+			fmt.Fprintf(w, "# TYPE %s gauge\n%s %d\n", metric, metric, m.Value())
+		case metrics.Timer:
+			fmt.Fprintf(w, "# TYPE %s summary\n%s_count %d\n%s_sum %d\n", metric, metric, m.Count(), metric, m.Sum())
+		}
+	})
+}
+
+func prometheusName(name string) string {
+	out := make([]byte, len(name))
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c == '.' {
+			c = '_'
+		}
+		out[i] = c
+	}
+	return string(out)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"snakegame/pkg/snake"
+)
+
+func TestObserveTickUpdatesCountersAndGauge(t *testing.T) {
+	r := NewRecorder()
+	prev := r.ObserveTick(snake.State{Snake: []snake.Position{{X: 0, Y: 0}, {X: 0, Y: 1}}, FoodEaten: 1}, true, 5*time.Millisecond, 0)
+	r.ObserveTick(snake.State{Snake: []snake.Position{{X: 0, Y: 0}, {X: 0, Y: 1}}, FoodEaten: 1}, false, 5*time.Millisecond, prev)
+
+	if got, want := r.Ticks.Count(), int64(2); got != want {
+		t.Fatalf("Ticks.Count() = %d, want %d", got, want)
+	}
+	if got, want := r.FoodsEaten.Count(), int64(1); got != want {
+		t.Fatalf("FoodsEaten.Count() = %d, want %d", got, want)
+	}
+	if got, want := r.Deaths.Count(), int64(1); got != want {
+		t.Fatalf("Deaths.Count() = %d, want %d", got, want)
+	}
+	if got, want := r.SnakeLength.Value(), int64(2); got != want {
+		t.Fatalf("SnakeLength.Value() = %d, want %d", got, want)
+	}
+}
+
+func TestHandlerServesJSON(t *testing.T) {
+	r := NewRecorder()
+	r.ObserveTick(snake.State{Snake: []snake.Position{{X: 0, Y: 0}}}, true, time.Millisecond, 0)
+
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	w := httptest.NewRecorder()
+	r.Handler().ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if _, ok := body["snake.ticks"]; !ok {
+		t.Fatalf("response %v missing snake.ticks", body)
+	}
+}
@@ -0,0 +1,229 @@
+// Package server exposes the snake engine over HTTP/JSON, so that bots or
+// alternative front-ends can play against the same core game that the
+// terminal UI in internal uses.
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	mathrand "math/rand"
+
+	"github.com/golang/glog"
+
+	"snakegame/pkg/metrics"
+	"snakegame/pkg/snake"
+)
+
+// directionsByName maps the wire representation of a direction to the engine's.
+var directionsByName = map[string]snake.Direction{
+	"down":  snake.Down,
+	"up":    snake.Up,
+	"left":  snake.Left,
+	"right": snake.Right,
+}
+
+// session is a single player's game, ticked on its own goroutine until it
+// dies or goes idle for too long.
+type session struct {
+	mu            sync.Mutex
+	game          *snake.Game
+	points        int
+	idleTicks     int
+	dir           snake.Direction
+	hasInput      bool
+	alive         bool
+	prevFoodEaten int
+}
+
+// Server serves the engine over HTTP. Each session gets its own board and
+// its own server-side ticker; the zero value is not usable, construct one
+// with NewServer.
+type Server struct {
+	Width, Height int
+	TickInterval  time.Duration
+	// IdleTTLTicks is how many consecutive ticks without new input a
+	// session tolerates before it's expired.
+	IdleTTLTicks int
+	// Metrics, if non-nil, is fed a sample of every session's every Tick.
+	Metrics *metrics.Recorder
+
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// NewServer returns a Server ready to accept sessions.
+func NewServer(width, height int, tickInterval time.Duration, idleTTLTicks int) *Server {
+	return &Server{
+		Width:        width,
+		Height:       height,
+		TickInterval: tickInterval,
+		IdleTTLTicks: idleTTLTicks,
+		sessions:     map[string]*session{},
+	}
+}
+
+// Handler returns the http.Handler serving the session, input, and
+// gamestate endpoints.
+func (srv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/session", srv.handleSession)
+	mux.HandleFunc("/input", srv.handleInput)
+	mux.HandleFunc("/gamestate", srv.handleGamestate)
+	return mux
+}
+
+func (srv *Server) handleSession(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	token, err := newToken()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("problem creating token: %v", err), http.StatusInternalServerError)
+		return
+	}
+	sess := &session{
+		game:  snake.NewGame(snake.Config{Width: srv.Width, Height: srv.Height, RandIntFn: mathrand.Intn}),
+		dir:   snake.Right,
+		alive: true,
+	}
+	srv.mu.Lock()
+	srv.sessions[token] = sess
+	srv.mu.Unlock()
+	go srv.run(token, sess)
+	writeJSON(w, map[string]string{"status": "ok", "token": token})
+}
+
+func (srv *Server) handleInput(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	sess, ok := srv.session(r.URL.Query().Get("token"))
+	if !ok {
+		http.Error(w, "unknown or expired token", http.StatusNotFound)
+		return
+	}
+	var body struct {
+		Direction string `json:"direction"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("problem decoding body: %v", err), http.StatusBadRequest)
+		return
+	}
+	dir, ok := directionsByName[strings.ToLower(body.Direction)]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown direction: %q", body.Direction), http.StatusBadRequest)
+		return
+	}
+	sess.mu.Lock()
+	sess.dir = dir
+	sess.hasInput = true
+	sess.mu.Unlock()
+	writeJSON(w, map[string]string{"status": "ok"})
+}
+
+func (srv *Server) handleGamestate(w http.ResponseWriter, r *http.Request) {
+	sess, ok := srv.session(r.URL.Query().Get("token"))
+	if !ok {
+		http.Error(w, "unknown or expired token", http.StatusNotFound)
+		return
+	}
+	sess.mu.Lock()
+	board := renderBoard(sess.game)
+	points := sess.points
+	alive := sess.alive
+	sess.mu.Unlock()
+	writeJSON(w, map[string]interface{}{
+		"board":  board,
+		"points": points,
+		"alive":  alive,
+	})
+}
+
+func (srv *Server) session(token string) (*session, bool) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	sess, ok := srv.sessions[token]
+	return sess, ok
+}
+
+// run ticks sess's game at srv.TickInterval, applying the most recently
+// queued input each tick, until the snake dies or the session goes idle
+// for IdleTTLTicks ticks.
+func (srv *Server) run(token string, sess *session) {
+	ticker := time.NewTicker(srv.TickInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		sess.mu.Lock()
+		dir := sess.dir
+		hadInput := sess.hasInput
+		sess.hasInput = false
+		start := time.Now()
+		state, alive := sess.game.Tick(dir)
+		if srv.Metrics != nil {
+			sess.prevFoodEaten = srv.Metrics.ObserveTick(state, alive, time.Since(start), sess.prevFoodEaten)
+		}
+		sess.alive = alive
+		sess.points = len(state.Snake) - 1
+		if hadInput {
+			sess.idleTicks = 0
+		} else {
+			sess.idleTicks++
+		}
+		points := sess.points
+		idle := sess.idleTicks
+		sess.mu.Unlock()
+		glog.V(2).Infof("session %s: points=%d alive=%v idle=%d", token, points, alive, idle)
+		if !alive || idle >= srv.IdleTTLTicks {
+			srv.mu.Lock()
+			delete(srv.sessions, token)
+			srv.mu.Unlock()
+			return
+		}
+	}
+}
+
+// renderBoard renders g's grid as rows of 's' (snake), 'f' (food), and '.'
+// (empty), joined by newlines.
+func renderBoard(g *snake.Game) string {
+	grid := g.Grid()
+	rows := make([]string, len(grid))
+	for y, row := range grid {
+		b := make([]byte, len(row))
+		for x, cell := range row {
+			switch cell {
+			case snake.CellSnake:
+				b[x] = 's'
+			case snake.CellFood:
+				b[x] = 'f'
+			default:
+				b[x] = '.'
+			}
+		}
+		rows[y] = string(b)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		glog.Errorf("problem encoding response: %v", err)
+	}
+}
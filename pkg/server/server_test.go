@@ -0,0 +1,74 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessionInputGamestate(t *testing.T) {
+	srv := NewServer(5, 5, 10*time.Millisecond, 100)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Post(ts.URL+"/session", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /session: %v", err)
+	}
+	var sessionResp struct {
+		Status string `json:"status"`
+		Token  string `json:"token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&sessionResp); err != nil {
+		t.Fatalf("decoding /session response: %v", err)
+	}
+	if sessionResp.Token == "" {
+		t.Fatalf("sessionResp.Token = %q, want non-empty", sessionResp.Token)
+	}
+
+	body, _ := json.Marshal(map[string]string{"direction": "down"})
+	resp, err = http.Post(ts.URL+"/input?token="+sessionResp.Token, "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /input: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("POST /input status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	resp, err = http.Get(ts.URL + "/gamestate?token=" + sessionResp.Token)
+	if err != nil {
+		t.Fatalf("GET /gamestate: %v", err)
+	}
+	var stateResp struct {
+		Board  string `json:"board"`
+		Points int    `json:"points"`
+		Alive  bool   `json:"alive"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&stateResp); err != nil {
+		t.Fatalf("decoding /gamestate response: %v", err)
+	}
+	if !stateResp.Alive {
+		t.Fatalf("stateResp.Alive = false, want true")
+	}
+	if got, want := strings.Count(stateResp.Board, "\n")+1, 5; got != want {
+		t.Fatalf("board has %d rows, want %d", got, want)
+	}
+}
+
+func TestGamestateUnknownTokenNotFound(t *testing.T) {
+	srv := NewServer(5, 5, 10*time.Millisecond, 100)
+	ts := httptest.NewServer(srv.Handler())
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/gamestate?token=bogus")
+	if err != nil {
+		t.Fatalf("GET /gamestate: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,231 @@
+package snake
+
+// Agent chooses the next move for a snake given the current game state.
+// Implementations must not retain or mutate the maps inside State.
+type Agent interface {
+	NextMove(state State) Direction
+}
+
+var allDirections = [4]Direction{Down, Up, Left, Right}
+
+// step returns the position one cell away from p in direction dir, wrapping
+// around the board if wraps is true.
+func step(p Position, dir Direction, width, height int, wraps bool) Position {
+	switch dir {
+	case Down:
+		p.Y++
+	case Up:
+		p.Y--
+	case Left:
+		p.X--
+	case Right:
+		p.X++
+	}
+	if wraps {
+		p.X = (p.X + width) % width
+		p.Y = (p.Y + height) % height
+	}
+	return p
+}
+
+// inBounds reports whether p is on a board of the given dimensions.
+func inBounds(p Position, width, height int) bool {
+	return p.X >= 0 && p.X < width && p.Y >= 0 && p.Y < height
+}
+
+// blockedCells returns the set of cells an agent must not move onto: the
+// obstacles, and the snake's own body except its tail (which Tick moves out
+// of the way before checking for collisions, even on a tick where the snake
+// eats).
+func blockedCells(state State) map[Position]struct{} {
+	blocked := map[Position]struct{}{}
+	for p := range state.Obstacles {
+		blocked[p] = struct{}{}
+	}
+	for i, p := range state.Snake {
+		if i == len(state.Snake)-1 {
+			continue
+		}
+		blocked[p] = struct{}{}
+	}
+	return blocked
+}
+
+// nearestFood returns the food cell with the smallest Manhattan distance
+// from p, and whether any food exists.
+func nearestFood(p Position, food map[Position]struct{}) (Position, bool) {
+	best := Position{}
+	bestDist := -1
+	for f := range food {
+		d := manhattan(p, f)
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = f, d
+		}
+	}
+	return best, bestDist != -1
+}
+
+func manhattan(a, b Position) int {
+	return abs(a.X-b.X) + abs(a.Y-b.Y)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// floodFill counts how many cells are reachable from start without
+// crossing a blocked cell or the edge of the board (unless it wraps).
+func floodFill(start Position, blocked map[Position]struct{}, width, height int, wraps bool) int {
+	if _, ok := blocked[start]; ok {
+		return 0
+	}
+	seen := map[Position]struct{}{start: {}}
+	queue := []Position{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, dir := range allDirections {
+			n := step(p, dir, width, height, wraps)
+			if !wraps && !inBounds(n, width, height) {
+				continue
+			}
+			if _, ok := seen[n]; ok {
+				continue
+			}
+			if _, ok := blocked[n]; ok {
+				continue
+			}
+			seen[n] = struct{}{}
+			queue = append(queue, n)
+		}
+	}
+	return len(seen)
+}
+
+// safeMoves returns the directions that don't immediately run the snake's
+// head into a wall, an obstacle, or its own body.
+func safeMoves(state State, blocked map[Position]struct{}) []Direction {
+	head := state.Snake[0]
+	var safe []Direction
+	for _, dir := range allDirections {
+		n := step(head, dir, state.Width, state.Height, state.Wraps)
+		if !state.Wraps && !inBounds(n, state.Width, state.Height) {
+			continue
+		}
+		if _, ok := blocked[n]; ok {
+			continue
+		}
+		safe = append(safe, dir)
+	}
+	return safe
+}
+
+// BFSAgent plays by breadth-first searching from the snake's head to the
+// nearest food each tick. If no path to any food exists, it falls back to
+// the move that leaves the most reachable free space, to survive as long
+// as possible.
+type BFSAgent struct{}
+
+// NextMove implements Agent.
+func (BFSAgent) NextMove(state State) Direction {
+	blocked := blockedCells(state)
+	if dir, ok := bfsToNearestFood(state, blocked); ok {
+		return dir
+	}
+	return survivalMove(state, blocked)
+}
+
+// bfsToNearestFood searches from the snake's head for the shortest path to
+// any food cell, treating blocked as impassable, and returns the first
+// step of that path.
+func bfsToNearestFood(state State, blocked map[Position]struct{}) (Direction, bool) {
+	head := state.Snake[0]
+	type queued struct {
+		pos   Position
+		first Direction
+	}
+	seen := map[Position]struct{}{head: {}}
+	queue := []queued{}
+	for _, dir := range allDirections {
+		n := step(head, dir, state.Width, state.Height, state.Wraps)
+		if !state.Wraps && !inBounds(n, state.Width, state.Height) {
+			continue
+		}
+		if _, ok := blocked[n]; ok {
+			continue
+		}
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		queue = append(queue, queued{n, dir})
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if _, ok := state.Food[cur.pos]; ok {
+			return cur.first, true
+		}
+		for _, dir := range allDirections {
+			n := step(cur.pos, dir, state.Width, state.Height, state.Wraps)
+			if !state.Wraps && !inBounds(n, state.Width, state.Height) {
+				continue
+			}
+			if _, ok := blocked[n]; ok {
+				continue
+			}
+			if _, ok := seen[n]; ok {
+				continue
+			}
+			seen[n] = struct{}{}
+			queue = append(queue, queued{n, cur.first})
+		}
+	}
+	return Down, false
+}
+
+// survivalMove returns the safe move that maximizes the reachable free
+// space from the resulting head position.
+func survivalMove(state State, blocked map[Position]struct{}) Direction {
+	head := state.Snake[0]
+	best := Down
+	bestSpace := -1
+	for _, dir := range safeMoves(state, blocked) {
+		n := step(head, dir, state.Width, state.Height, state.Wraps)
+		space := floodFill(n, blocked, state.Width, state.Height, state.Wraps)
+		if space > bestSpace {
+			best, bestSpace = dir, space
+		}
+	}
+	return best
+}
+
+// GreedyAgent plays by picking whichever safe move reduces the Manhattan
+// distance to the nearest food the most.
+type GreedyAgent struct{}
+
+// NextMove implements Agent.
+func (GreedyAgent) NextMove(state State) Direction {
+	blocked := blockedCells(state)
+	safe := safeMoves(state, blocked)
+	if len(safe) == 0 {
+		return Down
+	}
+	food, hasFood := nearestFood(state.Snake[0], state.Food)
+	best := safe[0]
+	bestDist := -1
+	for _, dir := range safe {
+		n := step(state.Snake[0], dir, state.Width, state.Height, state.Wraps)
+		d := 0
+		if hasFood {
+			d = manhattan(n, food)
+		}
+		if bestDist == -1 || d < bestDist {
+			best, bestDist = dir, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,57 @@
+package snake
+
+import "testing"
+
+func TestBFSAgentHeadsTowardFood(t *testing.T) {
+	state := State{
+		Snake:  []Position{{2, 2}},
+		Food:   map[Position]struct{}{{2, 5}: {}},
+		Width:  10,
+		Height: 10,
+	}
+	dir := (BFSAgent{}).NextMove(state)
+	if dir != Down {
+		t.Fatalf("NextMove() = %v, want Down", dir)
+	}
+}
+
+func TestBFSAgentSurvivesWhenFoodUnreachable(t *testing.T) {
+	// A wall spanning the whole height at x=4 separates the head from the
+	// food; the agent should fall back to a safe move instead of getting
+	// stuck searching for an unreachable path.
+	obstacles := map[Position]struct{}{}
+	for y := 0; y < 10; y++ {
+		obstacles[Position{4, y}] = struct{}{}
+	}
+	state := State{
+		Snake:     []Position{{1, 5}},
+		Food:      map[Position]struct{}{{8, 5}: {}},
+		Obstacles: obstacles,
+		Width:     10,
+		Height:    10,
+	}
+	dir := (BFSAgent{}).NextMove(state)
+	blocked := blockedCells(state)
+	n := step(state.Snake[0], dir, state.Width, state.Height, state.Wraps)
+	if !inBounds(n, state.Width, state.Height) {
+		t.Fatalf("NextMove() = %v, moves off the board to %+v", dir, n)
+	}
+	if _, ok := blocked[n]; ok {
+		t.Fatalf("NextMove() = %v, moves into a blocked cell %+v", dir, n)
+	}
+}
+
+func TestGreedyAgentAvoidsImmediateCollision(t *testing.T) {
+	// (0, 1) is a body segment, not the tail, so it's genuinely blocked;
+	// (0, 2) is the tail and will move out of the way, so it's not.
+	state := State{
+		Snake:  []Position{{0, 0}, {0, 1}, {0, 2}},
+		Food:   map[Position]struct{}{{5, 5}: {}},
+		Width:  10,
+		Height: 10,
+	}
+	dir := (GreedyAgent{}).NextMove(state)
+	if dir == Down {
+		t.Fatalf("NextMove() = Down, which collides with the snake's own body at {0, 1}")
+	}
+}
@@ -0,0 +1,266 @@
+// Package snake contains the headless, deterministic rules of the snake
+// game: movement, collision, food placement, and growth. It has no
+// dependency on any particular renderer, so it can be driven by a terminal
+// UI, a server, a test, or anything else that can produce a Direction each
+// tick.
+package snake
+
+// Direction is an input to the game: which way the snake's head should move
+// on the next Tick.
+type Direction int
+
+const (
+	Down Direction = iota
+	Up
+	Left
+	Right
+)
+
+// CellType identifies what occupies a cell in the grid returned by Grid.
+type CellType int
+
+const (
+	CellEmpty CellType = iota
+	CellSnake
+	CellFood
+	CellObstacle
+)
+
+// Mode selects how the board's edges and obstacles behave.
+type Mode int
+
+const (
+	// ModeClassic ends the game when the snake's head crosses the edge of
+	// the board.
+	ModeClassic Mode = iota
+	// ModeWrap lets the snake's head wrap around to the opposite edge
+	// instead of dying.
+	ModeWrap
+	// ModeObstacles is ModeClassic plus a set of obstacles, seeded at game
+	// start, that end the game on collision.
+	ModeObstacles
+)
+
+// Position is a coordinate on the board, (0, 0) at the upper-left corner.
+type Position struct {
+	X int
+	Y int
+}
+
+// State is a snapshot of the game after a Tick, including enough of the
+// board to let an Agent plan a move.
+type State struct {
+	Snake     []Position
+	Food      map[Position]struct{}
+	Obstacles map[Position]struct{}
+	Width     int
+	Height    int
+	Wraps     bool
+	Alive     bool
+	FoodEaten int
+}
+
+// Config configures a new Game.
+type Config struct {
+	// Width and Height are the board dimensions.
+	Width, Height int
+	// RandIntFn returns a pseudo-random, non-negative int less than n. It's
+	// injectable so that tests can seed deterministic food and obstacle
+	// placement.
+	RandIntFn func(n int) int
+	// Mode selects edge and obstacle behavior. The zero value is
+	// ModeClassic.
+	Mode Mode
+	// NumObstacles is how many obstacles to seed when Mode is
+	// ModeObstacles. Ignored otherwise.
+	NumObstacles int
+}
+
+// Game is a headless, deterministic snake game. The zero value is not
+// usable; construct one with NewGame.
+type Game struct {
+	Config
+
+	snake     []Position
+	food      map[Position]struct{}
+	obstacles map[Position]struct{}
+	alive     bool
+	foodEaten int
+}
+
+// NewGame returns a new Game with an initial snake, a single food cell, and
+// (in ModeObstacles) a set of randomly placed obstacles.
+func NewGame(cfg Config) *Game {
+	g := &Game{
+		Config: cfg,
+		snake:  []Position{{0, 0}},
+		food:   map[Position]struct{}{{0, 1}: {}},
+		alive:  true,
+	}
+	if cfg.Mode == ModeObstacles {
+		g.obstacles = g.placeObstacles(cfg.NumObstacles)
+	}
+	return g
+}
+
+// placeObstacles randomly seeds up to n obstacles, avoiding the snake's
+// starting position and the initial food. If n exceeds the number of free
+// cells on the board, it's clamped so callers can't request more obstacles
+// than fit.
+func (g *Game) placeObstacles(n int) map[Position]struct{} {
+	blocked := map[Position]struct{}{g.snake[0]: {}}
+	for p := range g.food {
+		blocked[p] = struct{}{}
+	}
+	free := make([]Position, 0, g.Width*g.Height-len(blocked))
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			p := Position{X: x, Y: y}
+			if _, ok := blocked[p]; !ok {
+				free = append(free, p)
+			}
+		}
+	}
+	if n > len(free) {
+		n = len(free)
+	}
+	obstacles := map[Position]struct{}{}
+	for len(obstacles) < n {
+		i := g.RandIntFn(len(free))
+		p := free[i]
+		free[i] = free[len(free)-1]
+		free = free[:len(free)-1]
+		obstacles[p] = struct{}{}
+	}
+	return obstacles
+}
+
+// Tick advances the game by one step in direction dir. It returns the
+// resulting State and whether the snake is still alive. Once the snake has
+// died, further calls to Tick are no-ops that keep returning the same dead
+// state.
+func (g *Game) Tick(dir Direction) (State, bool) {
+	if !g.alive {
+		return g.state(), false
+	}
+	// Save the tail so that if the snake ate food we can append the tail to
+	// grow by 1 unit.
+	tail := g.snake[len(g.snake)-1]
+	for i := len(g.snake) - 1; i > 0; i-- {
+		g.snake[i] = g.snake[i-1]
+	}
+	switch dir {
+	case Down:
+		g.snake[0].Y++
+	case Up:
+		g.snake[0].Y--
+	case Left:
+		g.snake[0].X--
+	case Right:
+		g.snake[0].X++
+	}
+	if g.Mode == ModeWrap {
+		g.snake[0].X = (g.snake[0].X + g.Width) % g.Width
+		g.snake[0].Y = (g.snake[0].Y + g.Height) % g.Height
+	} else if g.snake[0].X < 0 || g.snake[0].Y < 0 || g.snake[0].X >= g.Width || g.snake[0].Y >= g.Height {
+		// Range check the snake to end the game if it goes off the board.
+		g.alive = false
+		return g.state(), false
+	}
+	if _, ok := g.obstacles[g.snake[0]]; ok {
+		g.alive = false
+		return g.state(), false
+	}
+	// Check that the snake hasn't run into itself.
+	positions := map[Position]struct{}{}
+	for _, p := range g.snake {
+		if _, ok := positions[p]; ok {
+			g.alive = false
+			return g.state(), false
+		}
+		positions[p] = struct{}{}
+	}
+	// Grow the snake when it eats food.
+	if _, ok := g.food[g.snake[0]]; ok {
+		g.snake = append(g.snake, tail)
+		delete(g.food, g.snake[0])
+		g.foodEaten++
+		// If the board is full, leave food empty; the game effectively ends
+		// in a win since there's nowhere left for the snake to grow into.
+		if p, ok := g.placeFood(); ok {
+			g.food[p] = struct{}{}
+		}
+	}
+	return g.state(), true
+}
+
+// placeFood picks a random free cell for the next food, avoiding the
+// snake's own body and any obstacles so that growth never hands an agent a
+// move that looks safe but isn't. It reports false if the board is full and
+// no free cell exists.
+func (g *Game) placeFood() (Position, bool) {
+	occupied := map[Position]struct{}{}
+	for _, p := range g.snake {
+		occupied[p] = struct{}{}
+	}
+	for p := range g.obstacles {
+		occupied[p] = struct{}{}
+	}
+	free := make([]Position, 0, g.Width*g.Height-len(occupied))
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			p := Position{X: x, Y: y}
+			if _, ok := occupied[p]; !ok {
+				free = append(free, p)
+			}
+		}
+	}
+	if len(free) == 0 {
+		return Position{}, false
+	}
+	return free[g.RandIntFn(len(free))], true
+}
+
+// State returns a snapshot of the game's current state, without advancing
+// it. It can be called at any point, including before the first Tick.
+func (g *Game) State() State {
+	return g.state()
+}
+
+func (g *Game) state() State {
+	return State{
+		Snake:     g.snake,
+		Food:      g.food,
+		Obstacles: g.obstacles,
+		Width:     g.Width,
+		Height:    g.Height,
+		Wraps:     g.Mode == ModeWrap,
+		Alive:     g.alive,
+		FoodEaten: g.foodEaten,
+	}
+}
+
+// Grid renders the current game state as a Height-by-Width view of cell
+// types, indexed [y][x], for consumption by a renderer.
+func (g *Game) Grid() [][]CellType {
+	grid := make([][]CellType, g.Height)
+	for y := range grid {
+		grid[y] = make([]CellType, g.Width)
+	}
+	for p := range g.obstacles {
+		if p.Y >= 0 && p.Y < g.Height && p.X >= 0 && p.X < g.Width {
+			grid[p.Y][p.X] = CellObstacle
+		}
+	}
+	for p := range g.food {
+		if p.Y >= 0 && p.Y < g.Height && p.X >= 0 && p.X < g.Width {
+			grid[p.Y][p.X] = CellFood
+		}
+	}
+	for _, p := range g.snake {
+		if p.Y >= 0 && p.Y < g.Height && p.X >= 0 && p.X < g.Width {
+			grid[p.Y][p.X] = CellSnake
+		}
+	}
+	return grid
+}
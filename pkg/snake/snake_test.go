@@ -0,0 +1,105 @@
+package snake
+
+import "testing"
+
+// seededRandIntFn returns a RandIntFn that ignores n and returns the next
+// value from vals, repeating the last value once exhausted.
+func seededRandIntFn(vals ...int) func(int) int {
+	i := 0
+	return func(int) int {
+		v := vals[i]
+		if i < len(vals)-1 {
+			i++
+		}
+		return v
+	}
+}
+
+func TestTickEatsFoodAndPlacesNewFoodDeterministically(t *testing.T) {
+	g := NewGame(Config{Width: 10, Height: 10, RandIntFn: seededRandIntFn(5)})
+	// Snake starts at (0, 0), food at (0, 1). Moving down eats it. The new
+	// food is placed by indexing into the free cells in row-major order
+	// (skipping the snake's two occupied cells), so index 5 lands on (6, 0).
+	state, alive := g.Tick(Down)
+	if !alive {
+		t.Fatalf("Tick() alive = false, want true")
+	}
+	if len(state.Snake) != 2 {
+		t.Fatalf("len(state.Snake) = %d, want 2", len(state.Snake))
+	}
+	if _, ok := state.Food[Position{6, 0}]; !ok {
+		t.Fatalf("state.Food = %+v, want to contain {6, 0}", state.Food)
+	}
+	if state.FoodEaten != 1 {
+		t.Fatalf("state.FoodEaten = %d, want 1", state.FoodEaten)
+	}
+}
+
+func TestPlaceFoodReturnsFalseWhenBoardFull(t *testing.T) {
+	g := NewGame(Config{Width: 2, Height: 1, RandIntFn: seededRandIntFn(0)})
+	g.snake = []Position{{0, 0}, {1, 0}}
+	g.food = map[Position]struct{}{}
+	if _, ok := g.placeFood(); ok {
+		t.Fatalf("placeFood() ok = true, want false (board full)")
+	}
+}
+
+func TestTickLeavesFoodEmptyWhenBoardFull(t *testing.T) {
+	// A 1x2 board where eating the only food cell fills every remaining
+	// cell with snake; the next food placement must not hang.
+	g := NewGame(Config{Width: 1, Height: 2, RandIntFn: seededRandIntFn(0)})
+	state, alive := g.Tick(Down)
+	if !alive {
+		t.Fatalf("Tick(Down) alive = false, want true")
+	}
+	if len(state.Food) != 0 {
+		t.Fatalf("state.Food = %+v, want empty (board full)", state.Food)
+	}
+}
+
+func TestTickSelfCollisionEndsGame(t *testing.T) {
+	g := NewGame(Config{Width: 10, Height: 10, RandIntFn: seededRandIntFn(0)})
+	g.food = map[Position]struct{}{}
+	// A snake curled almost into a loop: moving down runs the head into
+	// where its own tail is about to be.
+	g.snake = []Position{{2, 2}, {3, 2}, {3, 3}, {2, 3}, {1, 3}}
+	if _, alive := g.Tick(Down); alive {
+		t.Fatalf("Tick(Down) alive = true, want false (self-collision)")
+	}
+}
+
+func TestTickOffBoardEndsGame(t *testing.T) {
+	g := NewGame(Config{Width: 2, Height: 2, RandIntFn: seededRandIntFn(0)})
+	if _, alive := g.Tick(Up); alive {
+		t.Fatalf("Tick(Up) alive = true, want false (off board)")
+	}
+}
+
+func TestTickWrapModeWrapsAroundEdge(t *testing.T) {
+	g := NewGame(Config{Width: 5, Height: 5, RandIntFn: seededRandIntFn(4, 4), Mode: ModeWrap})
+	if _, alive := g.Tick(Up); !alive {
+		t.Fatalf("Tick(Up) alive = false, want true (wrap mode)")
+	}
+	if got, want := g.snake[0], (Position{0, 4}); got != want {
+		t.Fatalf("g.snake[0] = %+v, want %+v", got, want)
+	}
+}
+
+func TestTickObstacleModeEndsGameOnCollision(t *testing.T) {
+	g := NewGame(Config{Width: 5, Height: 5, RandIntFn: seededRandIntFn(0), Mode: ModeObstacles})
+	g.obstacles = map[Position]struct{}{{0, 1}: {}}
+	g.food = map[Position]struct{}{}
+	if _, alive := g.Tick(Down); alive {
+		t.Fatalf("Tick(Down) alive = true, want false (obstacle collision)")
+	}
+}
+
+func TestNewGameClampsObstaclesToFreeCells(t *testing.T) {
+	// A 5x5 board has 25 cells, 2 of which are reserved for the snake's
+	// start and the initial food; requesting far more obstacles than fit
+	// must not hang, and should clamp to what's actually free.
+	g := NewGame(Config{Width: 5, Height: 5, RandIntFn: seededRandIntFn(0), Mode: ModeObstacles, NumObstacles: 30})
+	if got, want := len(g.obstacles), 23; got != want {
+		t.Fatalf("len(g.obstacles) = %d, want %d (clamped to free cells)", got, want)
+	}
+}